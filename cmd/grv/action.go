@@ -0,0 +1,41 @@
+package main
+
+// ActionType represents the type of an action that can be dispatched to a view
+type ActionType int
+
+// The set of actions a view may be asked to perform
+const (
+	ActionConfirm ActionType = iota
+	ActionShowStashDiff
+	ActionStashApply
+	ActionStashPop
+	ActionStashDrop
+	ActionStashShowDiff
+
+	ActionStageFile
+	ActionUnstageFile
+	ActionDiscardFile
+	ActionShowFileDiff
+	// ActionStageFileHunks opens the diff view scoped to a file with hunk-level
+	// staging/unstaging enabled, rather than a plain read-only diff
+	ActionStageFileHunks
+
+	// ActionOpenURL opens url (provided as the first Args entry) in the user's configured browser
+	ActionOpenURL
+	// ActionOpenCIRunURL opens the currently selected CI run's URL
+	ActionOpenCIRunURL
+
+	ActionSubmoduleInit
+	ActionSubmoduleUpdate
+	ActionSubmoduleSync
+	ActionSubmoduleDescend
+	// ActionRunCommandImmediate runs the command provided as the first Args entry without
+	// prompting for confirmation
+	ActionRunCommandImmediate
+)
+
+// Action represents a single action dispatched to a view, along with any arguments it requires
+type Action struct {
+	ActionType ActionType
+	Args       []interface{}
+}
@@ -0,0 +1,19 @@
+package main
+
+// ConfigVariableChangeListener is notified when a config variable's value changes
+type ConfigVariableChangeListener interface {
+	OnConfigVariableChange(configVariable string)
+}
+
+// Config provides read access to grv configuration variables and key bindings
+type Config interface {
+	GetBool(name string) bool
+	GetUint(name string) uint
+	GetString(name string) string
+
+	// AddOnChangeListener registers listener to be notified when the named config variable changes
+	AddOnChangeListener(name string, listener ConfigVariableChangeListener)
+
+	// KeyBindings returns the KeyBindingManager used to look up and register key bindings
+	KeyBindings() KeyBindingManager
+}
@@ -0,0 +1,25 @@
+package main
+
+// RepoController performs mutating operations against the repository on behalf of views
+type RepoController interface {
+	// ApplyStash applies stash to the working copy, leaving it in the stash list
+	ApplyStash(stash *Stash) error
+	// PopStash applies stash to the working copy and removes it from the stash list
+	PopStash(stash *Stash) error
+	// DropStash removes stash from the stash list without applying it
+	DropStash(stash *Stash) error
+
+	// StageFile stages the working copy changes for filePath
+	StageFile(filePath string) error
+	// UnstageFile unstages filePath, leaving its working copy changes in place
+	UnstageFile(filePath string) error
+	// DiscardFile discards the working copy changes for filePath
+	DiscardFile(filePath string) error
+
+	// SubmoduleUpdate initialises (if init is true) and/or updates the submodule at path,
+	// checking out the SHA recorded in the parent repo. If recursive is true nested submodules
+	// are updated too.
+	SubmoduleUpdate(path string, init, recursive bool) error
+	// SubmoduleSync updates the submodule's recorded URL from .gitmodules
+	SubmoduleSync(path string) error
+}
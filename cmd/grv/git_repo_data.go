@@ -0,0 +1,94 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// RefStateListener is notified when the set of refs or the current head changes
+type RefStateListener interface {
+	OnRefsChanged(addedRefs, removedRefs []Ref, updatedRefs []*UpdatedRef)
+	OnHeadChanged(oldHead, newHead Ref)
+	OnTrackingBranchesUpdated(trackingBranches []*LocalBranch)
+}
+
+// StatusListener is notified when the working copy/index status changes
+type StatusListener interface {
+	OnStatusChanged(status *Status)
+}
+
+// Stash represents a single stashed set of changes
+type Stash struct {
+	index   uint
+	branch  string
+	message string
+}
+
+// StashListener is notified when the set of stashes changes
+type StashListener interface {
+	OnStashesChanged(stashes []*Stash)
+}
+
+// RemoteDivergence describes how a local branch diverges from a single remote's copy of it
+type RemoteDivergence struct {
+	Remote      string
+	Ahead       uint
+	Behind      uint
+	LastFetched time.Time
+}
+
+// SubmodulesListener is notified when the set of submodules, or the status of an existing
+// submodule, changes
+type SubmodulesListener interface {
+	OnSubmodulesChanged(submodules []*Submodule)
+}
+
+// RepoData exposes read access to repository state and allows interested parties to be notified
+// of changes to that state
+type RepoData interface {
+	Head() Ref
+	Status() *Status
+
+	RegisterRefStateListener(listener RefStateListener)
+	UnregisterRefStateListener(listener RefStateListener)
+
+	RegisterStatusListener(listener StatusListener)
+	UnregisterStatusListener(listener StatusListener)
+
+	// Stashes returns the current set of stashes, most recent first
+	Stashes() []*Stash
+
+	RegisterStashListener(listener StashListener)
+	UnregisterStashListener(listener StashListener)
+
+	// RemoteDivergences returns how branch diverges from each configured remote
+	RemoteDivergences(branch *LocalBranch) []*RemoteDivergence
+
+	// CINotes returns up to maxRuns CI runs recorded against the current HEAD. It backs the
+	// notesCIStatusProvider (summary.ci.provider = "notes")
+	CINotes(maxRuns uint) ([]*CIRun, error)
+
+	// Submodules returns the current status of every submodule registered in .gitmodules
+	Submodules() []*Submodule
+
+	RegisterSubmodulesListener(listener SubmodulesListener)
+	UnregisterSubmodulesListener(listener SubmodulesListener)
+
+	// Path returns the absolute path to the repository's working directory
+	Path() string
+}
+
+// fetchHeadModTime returns the modification time of .git/FETCH_HEAD under repoPath, which is
+// rewritten by `git fetch` and is therefore used as the "last fetched" time for a remote. A zero
+// time is returned if the repo has never been fetched.
+func fetchHeadModTime(repoPath string) (modTime time.Time, err error) {
+	info, err := os.Stat(filepath.Join(repoPath, ".git", "FETCH_HEAD"))
+	if os.IsNotExist(err) {
+		return time.Time{}, nil
+	} else if err != nil {
+		return
+	}
+
+	return info.ModTime(), nil
+}
@@ -1,14 +1,53 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
 	"sync"
+	"time"
 
 	log "github.com/Sirupsen/logrus"
 )
 
 const (
 	svIndentationSpace = "     "
+
+	// CfSummarySections is the config variable controlling which summary view sections are
+	// displayed and the order they're displayed in
+	CfSummarySections = "summary.sections"
+
+	// DefaultSummarySections is the value of CfSummarySections when it has not been configured
+	DefaultSummarySections = "branch,files,stashes"
+
+	// CfSummaryBranchShowDivergenceGraph toggles the ahead/behind divergence graph in the
+	// Branch section
+	CfSummaryBranchShowDivergenceGraph = "summary.branch.show-divergence-graph"
+
+	// CfSummaryBranchDivergenceGraphWidth controls the width, in characters, of the ahead/behind
+	// divergence graph
+	CfSummaryBranchDivergenceGraphWidth = "summary.branch.divergence-graph-width"
+
+	// DefaultSummaryBranchDivergenceGraphWidth is the divergence graph width used when
+	// CfSummaryBranchDivergenceGraphWidth has not been configured
+	DefaultSummaryBranchDivergenceGraphWidth = 10
+
+	// CfSummaryCIProvider selects which CIStatusProvider populates the CI Status section: one of
+	// "notes", "gh" or "http"
+	CfSummaryCIProvider = "summary.ci.provider"
+
+	// CfSummaryCIMaxRuns caps the number of CI runs shown in the CI Status section
+	CfSummaryCIMaxRuns = "summary.ci.max-runs"
+
+	// CfSummaryCIHTTPURL is the endpoint queried by the "http" CIStatusProvider
+	CfSummaryCIHTTPURL = "summary.ci.http-url"
+
+	// DefaultSummaryCIMaxRuns is the value of CfSummaryCIMaxRuns when it has not been configured
+	DefaultSummaryCIMaxRuns = 5
 )
 
 type summaryViewHandler func(*SummaryView, Action) error
@@ -46,8 +85,84 @@ func newHeaderRenderer(header string) summaryViewLine {
 	}
 }
 
+const (
+	svDivergenceGraphAheadChar  = AcsBullet
+	svDivergenceGraphBehindChar = AcsCkboard
+	svDivergenceGraphSeparator  = AcsVline
+)
+
+// divergenceGraphChars computes how many ahead and behind characters to draw within width. The
+// two always sum to exactly width when ahead+behind > 0, so callers never overflow their budget.
+func divergenceGraphChars(ahead, behind, width uint) (aheadChars, behindChars uint) {
+	total := ahead + behind
+	if total == 0 || width == 0 {
+		return 0, 0
+	}
+
+	aheadChars = ahead * width / total
+	if ahead > 0 && aheadChars == 0 {
+		aheadChars = 1
+	}
+
+	behindChars = width - aheadChars
+	if behind > 0 && behindChars == 0 {
+		behindChars = 1
+		if aheadChars > 0 {
+			aheadChars--
+		}
+	}
+
+	return
+}
+
+// divergenceGraph renders a compact bar of ahead/behind characters scaled proportionally to width
+func divergenceGraph(lineBuilder *LineBuilder, ahead, behind uint, width uint) {
+	aheadChars, behindChars := divergenceGraphChars(ahead, behind, width)
+	if aheadChars == 0 && behindChars == 0 {
+		return
+	}
+
+	lineBuilder.AppendWithStyle(CmpSummaryViewNormal, " ")
+
+	for i := uint(0); i < aheadChars; i++ {
+		lineBuilder.AppendACSChar(svDivergenceGraphAheadChar, CmpSummaryViewDivergenceGraph)
+	}
+
+	lineBuilder.AppendACSChar(svDivergenceGraphSeparator, CmpSummaryViewNormal)
+
+	for i := uint(0); i < behindChars; i++ {
+		lineBuilder.AppendACSChar(svDivergenceGraphBehindChar, CmpSummaryViewDivergenceGraph)
+	}
+}
+
+// formatLastFetched returns a human readable "fetched N <unit>(s) ago" style string, or
+// "never fetched" for a zero time
+func formatLastFetched(lastFetched time.Time) string {
+	if lastFetched.IsZero() {
+		return "never fetched"
+	}
+
+	elapsed := time.Since(lastFetched)
+
+	switch {
+	case elapsed < time.Minute:
+		return "fetched less than a minute ago"
+	case elapsed < time.Hour:
+		minutes := uint(elapsed / time.Minute)
+		return fmt.Sprintf("fetched %v minute(s) ago", minutes)
+	case elapsed < 24*time.Hour:
+		hours := uint(elapsed / time.Hour)
+		return fmt.Sprintf("fetched %v hour(s) ago", hours)
+	default:
+		days := uint(elapsed / (24 * time.Hour))
+		return fmt.Sprintf("fetched %v day(s) ago", days)
+	}
+}
+
 type branchLine struct {
-	head Ref
+	head                 Ref
+	showDivergenceGraph  bool
+	divergenceGraphWidth uint
 }
 
 func (branchLine *branchLine) branchName() string {
@@ -69,6 +184,10 @@ func (branchLine *branchLine) render(lineBuilder *LineBuilder) {
 			AppendACSChar(AcsDarrow, CmpSummaryViewNormal).
 			AppendWithStyle(CmpSummaryViewBranchBehind, "%v", branch.behind).
 			AppendWithStyle(CmpSummaryViewNormal, ")")
+
+		if branchLine.showDivergenceGraph {
+			divergenceGraph(lineBuilder, branch.ahead, branch.behind, branchLine.divergenceGraphWidth)
+		}
 	}
 }
 
@@ -84,6 +203,297 @@ func (branchLine *branchLine) isSelectable() bool {
 	return true
 }
 
+type remoteDivergenceLine struct {
+	remoteDivergence     *RemoteDivergence
+	showDivergenceGraph  bool
+	divergenceGraphWidth uint
+}
+
+func (remoteDivergenceLine *remoteDivergenceLine) render(lineBuilder *LineBuilder) {
+	divergence := remoteDivergenceLine.remoteDivergence
+
+	lineBuilder.
+		AppendWithStyle(CmpSummaryViewNormal, "  %v (", divergence.Remote).
+		AppendACSChar(AcsUarrow, CmpSummaryViewNormal).
+		AppendWithStyle(CmpSummaryViewBranchAhead, "%v ", divergence.Ahead).
+		AppendACSChar(AcsDarrow, CmpSummaryViewNormal).
+		AppendWithStyle(CmpSummaryViewBranchBehind, "%v", divergence.Behind).
+		AppendWithStyle(CmpSummaryViewNormal, ")")
+
+	if remoteDivergenceLine.showDivergenceGraph {
+		divergenceGraph(lineBuilder, divergence.Ahead, divergence.Behind, remoteDivergenceLine.divergenceGraphWidth)
+	}
+
+	lineBuilder.AppendWithStyle(CmpSummaryViewNormal, " - %v", formatLastFetched(divergence.LastFetched))
+}
+
+func (remoteDivergenceLine *remoteDivergenceLine) renderString() string {
+	divergence := remoteDivergenceLine.remoteDivergence
+	return fmt.Sprintf("%v (^%v v%v) - %v", divergence.Remote, divergence.Ahead, divergence.Behind, formatLastFetched(divergence.LastFetched))
+}
+
+func (remoteDivergenceLine *remoteDivergenceLine) isSelectable() bool {
+	return false
+}
+
+// CIRunStatus represents the state of a single CI/CD pipeline run
+type CIRunStatus int
+
+// The set of states a CIRun can be in
+const (
+	CIRunQueued CIRunStatus = iota
+	CIRunRunning
+	CIRunSuccess
+	CIRunFailure
+)
+
+// CIRun describes a single CI/CD pipeline run against a commit
+type CIRun struct {
+	ID            string        `json:"id"`
+	Status        CIRunStatus   `json:"status"`
+	Duration      time.Duration `json:"duration"`
+	CommitSubject string        `json:"commitSubject"`
+	URL           string        `json:"url"`
+}
+
+// CIStatusProvider retrieves the most recent CI/CD runs for the current HEAD/branch
+type CIStatusProvider interface {
+	// Name identifies the provider, primarily for logging purposes
+	Name() string
+	// Runs returns at most maxRuns runs, newest first
+	Runs(repoData RepoData, maxRuns uint) ([]*CIRun, error)
+}
+
+// notesCIStatusProvider reads structured CI status from refs/notes/ci, allowing any CI system
+// to populate the summary view by writing a note describing its runs to that ref
+type notesCIStatusProvider struct{}
+
+func (notesCIStatusProvider) Name() string {
+	return "notes"
+}
+
+func (notesCIStatusProvider) Runs(repoData RepoData, maxRuns uint) ([]*CIRun, error) {
+	return repoData.CINotes(maxRuns)
+}
+
+// ghCIStatusProvider shells out to the GitHub CLI to list workflow runs for the current branch
+type ghCIStatusProvider struct{}
+
+func (ghCIStatusProvider) Name() string {
+	return "gh"
+}
+
+func (ghCIStatusProvider) Runs(repoData RepoData, maxRuns uint) (runs []*CIRun, err error) {
+	branch, isLocalBranch := repoData.Head().(*LocalBranch)
+	if !isLocalBranch {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ciStatusFetchTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "gh", "run", "list",
+		"--branch", branch.Shorthand(),
+		"--limit", fmt.Sprintf("%v", maxRuns),
+		"--json", "databaseId,status,conclusion,displayTitle,url,startedAt,updatedAt").Output()
+	if err != nil {
+		return nil, fmt.Errorf("Unable to list gh runs: %v", err)
+	}
+
+	var ghRuns []struct {
+		DatabaseID   int    `json:"databaseId"`
+		Status       string `json:"status"`
+		Conclusion   string `json:"conclusion"`
+		DisplayTitle string `json:"displayTitle"`
+		URL          string `json:"url"`
+		StartedAt    time.Time
+		UpdatedAt    time.Time
+	}
+
+	if err = json.Unmarshal(output, &ghRuns); err != nil {
+		return nil, fmt.Errorf("Unable to parse gh run list output: %v", err)
+	}
+
+	for _, ghRun := range ghRuns {
+		runs = append(runs, &CIRun{
+			ID:            fmt.Sprintf("%v", ghRun.DatabaseID),
+			Status:        ghRunStatus(ghRun.Status, ghRun.Conclusion),
+			Duration:      ghRunDuration(ghRun.StartedAt, ghRun.UpdatedAt),
+			CommitSubject: ghRun.DisplayTitle,
+			URL:           ghRun.URL,
+		})
+	}
+
+	return
+}
+
+// unknownCIRunDuration is used as CIRun.Duration when a run's start time isn't known yet (e.g. a
+// queued run), so formatCIRunDuration can render it as "--:--" instead of a bogus elapsed time
+const unknownCIRunDuration = -1 * time.Second
+
+// ghRunDuration computes how long a gh run has taken. startedAt is the JSON zero-time for runs
+// that haven't started yet, which would otherwise make the subtraction yield a multi-century
+// duration.
+func ghRunDuration(startedAt, updatedAt time.Time) time.Duration {
+	if startedAt.IsZero() {
+		return unknownCIRunDuration
+	}
+
+	return updatedAt.Sub(startedAt)
+}
+
+func ghRunStatus(status, conclusion string) CIRunStatus {
+	switch status {
+	case "queued":
+		return CIRunQueued
+	case "in_progress":
+		return CIRunRunning
+	}
+
+	if conclusion == "success" {
+		return CIRunSuccess
+	}
+
+	return CIRunFailure
+}
+
+// httpJSONCIStatusProvider fetches runs from a generic HTTP endpoint returning a JSON array of CIRun
+type httpJSONCIStatusProvider struct {
+	url string
+}
+
+func (provider httpJSONCIStatusProvider) Name() string {
+	return "http"
+}
+
+var ciStatusHTTPClient = &http.Client{
+	Timeout: ciStatusFetchTimeout,
+}
+
+func (provider httpJSONCIStatusProvider) Runs(repoData RepoData, maxRuns uint) (runs []*CIRun, err error) {
+	resp, err := ciStatusHTTPClient.Get(provider.url)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to fetch CI status from %v: %v", provider.url, err)
+	}
+	defer resp.Body.Close()
+
+	if err = json.NewDecoder(resp.Body).Decode(&runs); err != nil {
+		return nil, fmt.Errorf("Unable to parse CI status response from %v: %v", provider.url, err)
+	}
+
+	if uint(len(runs)) > maxRuns {
+		runs = runs[:maxRuns]
+	}
+
+	return
+}
+
+func formatCIRunDuration(duration time.Duration) string {
+	if duration < 0 {
+		return "--:--"
+	}
+
+	minutes := uint(duration / time.Minute)
+	seconds := uint((duration % time.Minute) / time.Second)
+	return fmt.Sprintf("%02d:%02d", minutes, seconds)
+}
+
+type ciStatusLine struct {
+	run *CIRun
+}
+
+func (ciStatusLine *ciStatusLine) statusText() string {
+	switch ciStatusLine.run.Status {
+	case CIRunSuccess:
+		return "success"
+	case CIRunFailure:
+		return "failure"
+	case CIRunRunning:
+		return "running"
+	default:
+		return "queued"
+	}
+}
+
+func (ciStatusLine *ciStatusLine) statusThemeComponentID() ThemeComponentID {
+	switch ciStatusLine.run.Status {
+	case CIRunSuccess:
+		return CmpSummaryViewCIStatusSuccess
+	case CIRunFailure:
+		return CmpSummaryViewCIStatusFailure
+	case CIRunRunning:
+		return CmpSummaryViewCIStatusRunning
+	default:
+		return CmpSummaryViewCIStatusQueued
+	}
+}
+
+func (ciStatusLine *ciStatusLine) render(lineBuilder *LineBuilder) {
+	run := ciStatusLine.run
+
+	lineBuilder.
+		AppendWithStyle(ciStatusLine.statusThemeComponentID(), "%-7v", ciStatusLine.statusText()).
+		AppendWithStyle(CmpSummaryViewNormal, " %v ", formatCIRunDuration(run.Duration)).
+		AppendWithStyle(CmpSummaryViewNormal, "%v", run.CommitSubject)
+}
+
+func (ciStatusLine *ciStatusLine) renderString() string {
+	run := ciStatusLine.run
+	return fmt.Sprintf("%v %v %v", ciStatusLine.statusText(), formatCIRunDuration(run.Duration), run.CommitSubject)
+}
+
+func (ciStatusLine *ciStatusLine) isSelectable() bool {
+	return true
+}
+
+// Submodule describes the status of a single git submodule relative to the parent repo
+type Submodule struct {
+	Path          string
+	URL           string
+	SHA           string
+	RecordedSHA   string
+	Uninitialized bool
+	Conflicted    bool
+}
+
+type submoduleLine struct {
+	submodule *Submodule
+}
+
+// statusFlag returns "-" for an uninitialised submodule, "+" when the checked out SHA differs
+// from the SHA recorded in the parent repo, "U" when the submodule has a merge conflict, or a
+// single space when it is up to date
+func (submoduleLine *submoduleLine) statusFlag() string {
+	switch {
+	case submoduleLine.submodule.Conflicted:
+		return "U"
+	case submoduleLine.submodule.Uninitialized:
+		return "-"
+	case submoduleLine.submodule.SHA != submoduleLine.submodule.RecordedSHA:
+		return "+"
+	default:
+		return " "
+	}
+}
+
+func (submoduleLine *submoduleLine) render(lineBuilder *LineBuilder) {
+	submodule := submoduleLine.submodule
+
+	lineBuilder.
+		AppendWithStyle(CmpSummaryViewSubmoduleStatus, "%v", submoduleLine.statusFlag()).
+		AppendWithStyle(CmpSummaryViewNormal, " %v", submodule.Path).
+		AppendWithStyle(CmpSummaryViewNormal, " (%v)", submodule.URL)
+}
+
+func (submoduleLine *submoduleLine) renderString() string {
+	submodule := submoduleLine.submodule
+	return fmt.Sprintf("%v %v (%v)", submoduleLine.statusFlag(), submodule.Path, submodule.URL)
+}
+
+func (submoduleLine *submoduleLine) isSelectable() bool {
+	return true
+}
+
 type statusFileLine struct {
 	statusType  StatusType
 	statusEntry *StatusEntry
@@ -144,6 +554,87 @@ func (statusFileLine *statusFileLine) isSelectable() bool {
 	return true
 }
 
+type stashLine struct {
+	stash *Stash
+}
+
+func (stashLine *stashLine) render(lineBuilder *LineBuilder) {
+	lineBuilder.
+		AppendWithStyle(CmpSummaryViewStashIndex, "stash@{%v}", stashLine.stash.index).
+		AppendWithStyle(CmpSummaryViewNormal, " %v: %v", stashLine.stash.branch, stashLine.stash.message)
+}
+
+func (stashLine *stashLine) renderString() string {
+	return fmt.Sprintf("stash@{%v} %v: %v", stashLine.stash.index, stashLine.stash.branch, stashLine.stash.message)
+}
+
+func (stashLine *stashLine) isSelectable() bool {
+	return true
+}
+
+// SummarySectionProvider generates the rows for a single, named summary view section and
+// declares the repo listeners it depends on, so SummaryView can subscribe and unsubscribe
+// sections as the enabled set is (re)configured
+type SummarySectionProvider struct {
+	Generate    func(summaryView *SummaryView) []summaryViewLine
+	Subscribe   func(summaryView *SummaryView)
+	Unsubscribe func(summaryView *SummaryView)
+}
+
+var summarySectionProviders = map[string]SummarySectionProvider{}
+
+// RegisterSummarySection registers a SummarySectionProvider under name, making it available for
+// selection in the summary.sections config variable
+func RegisterSummarySection(name string, provider SummarySectionProvider) {
+	summarySectionProviders[name] = provider
+}
+
+func init() {
+	RegisterSummarySection("branch", SummarySectionProvider{
+		Generate: (*SummaryView).generateBranchRows,
+		Subscribe: func(summaryView *SummaryView) {
+			summaryView.repoData.RegisterRefStateListener(summaryView)
+		},
+		Unsubscribe: func(summaryView *SummaryView) {
+			summaryView.repoData.UnregisterRefStateListener(summaryView)
+		},
+	})
+
+	RegisterSummarySection("files", SummarySectionProvider{
+		Generate: (*SummaryView).generateModifiedFiles,
+		Subscribe: func(summaryView *SummaryView) {
+			summaryView.repoData.RegisterStatusListener(summaryView)
+		},
+		Unsubscribe: func(summaryView *SummaryView) {
+			summaryView.repoData.UnregisterStatusListener(summaryView)
+		},
+	})
+
+	RegisterSummarySection("stashes", SummarySectionProvider{
+		Generate: (*SummaryView).generateStashes,
+		Subscribe: func(summaryView *SummaryView) {
+			summaryView.repoData.RegisterStashListener(summaryView)
+		},
+		Unsubscribe: func(summaryView *SummaryView) {
+			summaryView.repoData.UnregisterStashListener(summaryView)
+		},
+	})
+
+	RegisterSummarySection("ci", SummarySectionProvider{
+		Generate: (*SummaryView).generateCIStatus,
+	})
+
+	RegisterSummarySection("submodules", SummarySectionProvider{
+		Generate: (*SummaryView).generateSubmodules,
+		Subscribe: func(summaryView *SummaryView) {
+			summaryView.repoData.RegisterSubmodulesListener(summaryView)
+		},
+		Unsubscribe: func(summaryView *SummaryView) {
+			summaryView.repoData.UnregisterSubmodulesListener(summaryView)
+		},
+	})
+}
+
 // SummaryView displays a summary view of repo state
 type SummaryView struct {
 	*SelectableRowView
@@ -155,8 +646,14 @@ type SummaryView struct {
 	lastViewDimension ViewDimension
 	variables         GRVVariableSetter
 	handlers          map[ActionType]summaryViewHandler
+	sections          []SummarySectionProvider
 	lines             []summaryViewLine
 	lock              sync.Mutex
+	ciStatusLock      sync.Mutex
+	ciStatusRuns      []*CIRun
+	ciStatusErr       error
+	ciStatusFetching  bool
+	ciStatusFetchedAt time.Time
 }
 
 // NewGitSummaryView creates a new summary view instance
@@ -173,6 +670,28 @@ func NewGitSummaryView(repoData RepoData, repoController RepoController, channel
 
 	summaryView.SelectableRowView = NewSelectableRowView(summaryView, channels, config, variables, &summaryView.lock, "summary row")
 
+	summaryView.handlers[ActionStashApply] = applySelectedStash
+	summaryView.handlers[ActionStashPop] = popSelectedStash
+	summaryView.handlers[ActionStashDrop] = dropSelectedStash
+	summaryView.handlers[ActionStashShowDiff] = showSelectedStashDiff
+	registerSummaryViewStashKeyBindings(config.KeyBindings())
+
+	summaryView.handlers[ActionStageFile] = stageSelectedFile
+	summaryView.handlers[ActionUnstageFile] = unstageSelectedFile
+	summaryView.handlers[ActionDiscardFile] = discardSelectedFile
+	summaryView.handlers[ActionShowFileDiff] = showSelectedFileDiff
+	summaryView.handlers[ActionStageFileHunks] = stageSelectedFileHunks
+	registerSummaryViewFileKeyBindings(config.KeyBindings())
+
+	summaryView.handlers[ActionOpenCIRunURL] = openSelectedCIRun
+	registerSummaryViewCIKeyBindings(config.KeyBindings())
+
+	summaryView.handlers[ActionSubmoduleInit] = initSelectedSubmodule
+	summaryView.handlers[ActionSubmoduleUpdate] = updateSelectedSubmodule
+	summaryView.handlers[ActionSubmoduleSync] = syncSelectedSubmodule
+	summaryView.handlers[ActionSubmoduleDescend] = descendIntoSelectedSubmodule
+	registerSummaryViewSubmoduleKeyBindings(config.KeyBindings())
+
 	return summaryView
 }
 
@@ -181,12 +700,68 @@ func (summaryView *SummaryView) Initialise() (err error) {
 	summaryView.lock.Lock()
 	defer summaryView.lock.Unlock()
 
-	summaryView.repoData.RegisterRefStateListener(summaryView)
-	summaryView.repoData.RegisterStatusListener(summaryView)
+	summaryView.config.AddOnChangeListener(CfSummarySections, summaryView)
+	summaryView.configureSections()
 	summaryView.generateRows()
 	return summaryView.selectNearestSelectableRow()
 }
 
+// configureSections builds the ordered, enabled section list from the summary.sections config
+// variable and subscribes to the repo listeners each enabled section depends on
+func (summaryView *SummaryView) configureSections() {
+	sectionsConfig := summaryView.config.GetString(CfSummarySections)
+	if sectionsConfig == "" {
+		sectionsConfig = DefaultSummarySections
+	}
+
+	sections := make([]SummarySectionProvider, 0, len(summarySectionProviders))
+
+	for _, name := range strings.Split(sectionsConfig, ",") {
+		name = strings.TrimSpace(name)
+
+		provider, ok := summarySectionProviders[name]
+		if !ok {
+			log.Errorf("Unknown summary view section %q configured for %v", name, CfSummarySections)
+			continue
+		}
+
+		sections = append(sections, provider)
+
+		if provider.Subscribe != nil {
+			provider.Subscribe(summaryView)
+		}
+	}
+
+	summaryView.sections = sections
+}
+
+// unsubscribeSections unsubscribes the repo listeners used by the currently enabled sections
+func (summaryView *SummaryView) unsubscribeSections() {
+	for _, section := range summaryView.sections {
+		if section.Unsubscribe != nil {
+			section.Unsubscribe(summaryView)
+		}
+	}
+}
+
+// OnConfigVariableChange reconfigures the enabled summary sections when summary.sections changes
+func (summaryView *SummaryView) OnConfigVariableChange(configVariable string) {
+	if configVariable != CfSummarySections {
+		return
+	}
+
+	summaryView.lock.Lock()
+	defer summaryView.lock.Unlock()
+
+	summaryView.unsubscribeSections()
+	summaryView.configureSections()
+	summaryView.generateRows()
+
+	if err := summaryView.selectNearestSelectableRow(); err != nil {
+		log.Errorf("Unable to select nearest selectable row after reconfiguring summary sections: %v", err)
+	}
+}
+
 // Render generates and writes the summary view to the provided window
 func (summaryView *SummaryView) Render(win RenderWindow) (err error) {
 	summaryView.lock.Lock()
@@ -272,21 +847,47 @@ func (summaryView *SummaryView) isSelectableRow(rowIndex uint) (isSelectable boo
 }
 
 func (summaryView *SummaryView) generateRows() {
-	lines := summaryView.generateBranchRows()
-	lines = append(lines, summaryView.generateModifiedFiles()...)
+	var lines []summaryViewLine
+
+	for _, section := range summaryView.sections {
+		lines = append(lines, section.Generate(summaryView)...)
+	}
+
 	summaryView.lines = lines
 	summaryView.channels.UpdateDisplay()
 }
 
 func (summaryView *SummaryView) generateBranchRows() (rows []summaryViewLine) {
 	ref := summaryView.repoData.Head()
+
+	showDivergenceGraph := summaryView.config.GetBool(CfSummaryBranchShowDivergenceGraph)
+	divergenceGraphWidth := summaryView.config.GetUint(CfSummaryBranchDivergenceGraphWidth)
+	if divergenceGraphWidth == 0 {
+		divergenceGraphWidth = DefaultSummaryBranchDivergenceGraphWidth
+	}
+
 	rows = append(rows,
 		emptyLine,
 		newHeaderRenderer("Branch"),
-		&branchLine{head: ref},
-		emptyLine,
+		&branchLine{
+			head:                 ref,
+			showDivergenceGraph:  showDivergenceGraph,
+			divergenceGraphWidth: divergenceGraphWidth,
+		},
 	)
 
+	if branch, isLocalBranch := ref.(*LocalBranch); isLocalBranch {
+		for _, remoteDivergence := range summaryView.repoData.RemoteDivergences(branch) {
+			rows = append(rows, &remoteDivergenceLine{
+				remoteDivergence:     remoteDivergence,
+				showDivergenceGraph:  showDivergenceGraph,
+				divergenceGraphWidth: divergenceGraphWidth,
+			})
+		}
+	}
+
+	rows = append(rows, emptyLine)
+
 	return
 }
 
@@ -324,6 +925,246 @@ func (summaryView *SummaryView) generateModifiedFiles() (rows []summaryViewLine)
 	return
 }
 
+func (summaryView *SummaryView) generateStashes() (rows []summaryViewLine) {
+	rows = append(rows,
+		emptyLine,
+		newHeaderRenderer("Stashes"),
+	)
+
+	stashes := summaryView.repoData.Stashes()
+	if len(stashes) == 0 {
+		rows = append(rows, &singleValueLine{
+			value:            "None",
+			themeComponentID: CmpSummaryViewNoModifiedFiles,
+		})
+
+		return
+	}
+
+	for _, stash := range stashes {
+		rows = append(rows, &stashLine{stash: stash})
+	}
+
+	rows = append(rows, emptyLine)
+
+	return
+}
+
+func (summaryView *SummaryView) generateCIStatus() (rows []summaryViewLine) {
+	rows = append(rows,
+		emptyLine,
+		newHeaderRenderer("CI Status"),
+	)
+
+	provider := summaryView.ciStatusProvider()
+	if provider == nil {
+		rows = append(rows, &singleValueLine{
+			value:            "No CI status provider configured",
+			themeComponentID: CmpSummaryViewNoModifiedFiles,
+		})
+
+		return
+	}
+
+	summaryView.refreshCIStatusAsync(provider)
+
+	runs, fetched, err := summaryView.cachedCIStatus()
+	switch {
+	case err != nil:
+		rows = append(rows, &singleValueLine{
+			value:            "Unable to fetch CI status",
+			themeComponentID: CmpSummaryViewNoModifiedFiles,
+		})
+	case !fetched:
+		rows = append(rows, &singleValueLine{
+			value:            "Fetching CI status...",
+			themeComponentID: CmpSummaryViewNoModifiedFiles,
+		})
+	case len(runs) == 0:
+		rows = append(rows, &singleValueLine{
+			value:            "None",
+			themeComponentID: CmpSummaryViewNoModifiedFiles,
+		})
+	default:
+		for _, run := range runs {
+			rows = append(rows, &ciStatusLine{run: run})
+		}
+
+		rows = append(rows, emptyLine)
+	}
+
+	return
+}
+
+// ciStatusFetchTimeout bounds how long a CI status provider fetch is waited on. If a provider
+// (a hung gh process or an unreachable HTTP endpoint) doesn't respond within this time its result
+// is discarded rather than blocking the caller.
+const ciStatusFetchTimeout = 5 * time.Second
+
+// ciStatusMinRefreshInterval is the minimum time that must elapse since the last completed fetch
+// before another one is started. generateCIStatus is invoked every time the CI section is
+// (re-)rendered, which itself happens as a result of refreshCIStatusAsync completing, so without
+// this guard a successful fetch would immediately trigger another, in an unthrottled loop.
+const ciStatusMinRefreshInterval = 30 * time.Second
+
+// refreshCIStatusAsync kicks off a fetch of the latest CI runs on a separate goroutine, so that
+// generateCIStatus (called with summaryView.lock held, from every repo change handler) never
+// blocks on network or process I/O. The cached result populated here is what generateCIStatus
+// reads on every call; OnConfigVariableChange and the repo change handlers therefore only ever
+// see a stale-but-cached result immediately, with a fresh one arriving asynchronously. A fetch is
+// skipped if one is already in flight, or if the last completed fetch finished less than
+// ciStatusMinRefreshInterval ago.
+func (summaryView *SummaryView) refreshCIStatusAsync(provider CIStatusProvider) {
+	summaryView.ciStatusLock.Lock()
+	if summaryView.ciStatusFetching || time.Since(summaryView.ciStatusFetchedAt) < ciStatusMinRefreshInterval {
+		summaryView.ciStatusLock.Unlock()
+		return
+	}
+	summaryView.ciStatusFetching = true
+	summaryView.ciStatusLock.Unlock()
+
+	maxRuns := summaryView.config.GetUint(CfSummaryCIMaxRuns)
+	if maxRuns == 0 {
+		maxRuns = DefaultSummaryCIMaxRuns
+	}
+
+	go func() {
+		runs, err := fetchCIStatusWithTimeout(provider, summaryView.repoData, maxRuns, ciStatusFetchTimeout)
+		if err != nil {
+			log.Errorf("Unable to fetch CI status from provider %v: %v", provider.Name(), err)
+		}
+
+		summaryView.ciStatusLock.Lock()
+		summaryView.ciStatusRuns = runs
+		summaryView.ciStatusErr = err
+		summaryView.ciStatusFetching = false
+		summaryView.ciStatusFetchedAt = time.Now()
+		summaryView.ciStatusLock.Unlock()
+
+		summaryView.lock.Lock()
+		summaryView.generateRows()
+		summaryView.lock.Unlock()
+	}()
+}
+
+// cachedCIStatus returns the result of the most recently completed CI status fetch. fetched is
+// false until the first fetch has returned.
+func (summaryView *SummaryView) cachedCIStatus() (runs []*CIRun, fetched bool, err error) {
+	summaryView.ciStatusLock.Lock()
+	defer summaryView.ciStatusLock.Unlock()
+
+	return summaryView.ciStatusRuns, !summaryView.ciStatusFetchedAt.IsZero(), summaryView.ciStatusErr
+}
+
+// fetchCIStatusWithTimeout runs provider.Runs on a separate goroutine and abandons waiting on it
+// once timeout elapses, so a hung gh process or unresponsive HTTP endpoint can never block the
+// caller. The abandoned goroutine is left to finish (or leak, in the hung-gh-process case) on its
+// own; its result is discarded.
+func fetchCIStatusWithTimeout(provider CIStatusProvider, repoData RepoData, maxRuns uint, timeout time.Duration) ([]*CIRun, error) {
+	type result struct {
+		runs []*CIRun
+		err  error
+	}
+
+	resultCh := make(chan result, 1)
+
+	go func() {
+		runs, err := provider.Runs(repoData, maxRuns)
+		resultCh <- result{runs, err}
+	}()
+
+	select {
+	case res := <-resultCh:
+		return res.runs, res.err
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("Timed out after %v waiting for CI status provider %v", timeout, provider.Name())
+	}
+}
+
+func (summaryView *SummaryView) generateSubmodules() (rows []summaryViewLine) {
+	rows = append(rows,
+		emptyLine,
+		newHeaderRenderer("Submodules"),
+	)
+
+	submodules := summaryView.repoData.Submodules()
+	if len(submodules) == 0 {
+		rows = append(rows, &singleValueLine{
+			value:            "None",
+			themeComponentID: CmpSummaryViewNoModifiedFiles,
+		})
+
+		return
+	}
+
+	for _, submodule := range submodules {
+		rows = append(rows, &submoduleLine{submodule: submodule})
+	}
+
+	rows = append(rows, emptyLine)
+
+	return
+}
+
+// selectedSubmodule returns the submodule corresponding to the currently selected row, if any
+func (summaryView *SummaryView) selectedSubmodule() (submodule *Submodule, isSubmodule bool) {
+	if rowIndex := summaryView.viewPos().SelectedRowIndex(); rowIndex < summaryView.rows() {
+		if line, ok := summaryView.lines[rowIndex].(*submoduleLine); ok {
+			return line.submodule, true
+		}
+	}
+
+	return
+}
+
+// ciStatusProvider returns the CIStatusProvider configured via summary.ci.provider, or nil if
+// none is configured
+func (summaryView *SummaryView) ciStatusProvider() CIStatusProvider {
+	switch summaryView.config.GetString(CfSummaryCIProvider) {
+	case "notes":
+		return notesCIStatusProvider{}
+	case "gh":
+		return ghCIStatusProvider{}
+	case "http":
+		if url := summaryView.config.GetString(CfSummaryCIHTTPURL); url != "" {
+			return httpJSONCIStatusProvider{url: url}
+		}
+	}
+
+	return nil
+}
+
+// selectedStash returns the stash corresponding to the currently selected row, if any
+func (summaryView *SummaryView) selectedStash() (stash *Stash, isStash bool) {
+	if rowIndex := summaryView.viewPos().SelectedRowIndex(); rowIndex < summaryView.rows() {
+		if line, ok := summaryView.lines[rowIndex].(*stashLine); ok {
+			return line.stash, true
+		}
+	}
+
+	return
+}
+
+// selectedStatusFile returns the status file line corresponding to the currently selected row, if any
+func (summaryView *SummaryView) selectedStatusFile() (statusFileLine *statusFileLine, isStatusFile bool) {
+	if rowIndex := summaryView.viewPos().SelectedRowIndex(); rowIndex < summaryView.rows() {
+		statusFileLine, isStatusFile = summaryView.lines[rowIndex].(*statusFileLine)
+	}
+
+	return
+}
+
+// selectedCIRun returns the CI run corresponding to the currently selected row, if any
+func (summaryView *SummaryView) selectedCIRun() (run *CIRun, isCIRun bool) {
+	if rowIndex := summaryView.viewPos().SelectedRowIndex(); rowIndex < summaryView.rows() {
+		if line, ok := summaryView.lines[rowIndex].(*ciStatusLine); ok {
+			return line.run, true
+		}
+	}
+
+	return
+}
+
 // OnRefsChanged regenerates the summary view
 func (summaryView *SummaryView) OnRefsChanged(addedRefs, removedRefs []Ref, updatedRefs []*UpdatedRef) {
 	summaryView.lock.Lock()
@@ -356,6 +1197,22 @@ func (summaryView *SummaryView) OnStatusChanged(status *Status) {
 	summaryView.generateRows()
 }
 
+// OnStashesChanged regenerates the summary view
+func (summaryView *SummaryView) OnStashesChanged(stashes []*Stash) {
+	summaryView.lock.Lock()
+	defer summaryView.lock.Unlock()
+
+	summaryView.generateRows()
+}
+
+// OnSubmodulesChanged regenerates the summary view
+func (summaryView *SummaryView) OnSubmodulesChanged(submodules []*Submodule) {
+	summaryView.lock.Lock()
+	defer summaryView.lock.Unlock()
+
+	summaryView.generateRows()
+}
+
 // HandleAction checks if the summary view supports the provided action and executes it if so
 func (summaryView *SummaryView) HandleAction(action Action) (err error) {
 	summaryView.lock.Lock()
@@ -373,3 +1230,185 @@ func (summaryView *SummaryView) HandleAction(action Action) (err error) {
 
 	return
 }
+
+func applySelectedStash(summaryView *SummaryView, action Action) (err error) {
+	stash, isStash := summaryView.selectedStash()
+	if !isStash {
+		return
+	}
+
+	return summaryView.repoController.ApplyStash(stash)
+}
+
+func popSelectedStash(summaryView *SummaryView, action Action) (err error) {
+	stash, isStash := summaryView.selectedStash()
+	if !isStash {
+		return
+	}
+
+	return summaryView.repoController.PopStash(stash)
+}
+
+func dropSelectedStash(summaryView *SummaryView, action Action) (err error) {
+	stash, isStash := summaryView.selectedStash()
+	if !isStash {
+		return
+	}
+
+	summaryView.channels.DoAction(Action{
+		ActionType: ActionConfirm,
+		Args: []interface{}{
+			fmt.Sprintf("Are you sure you want to drop stash@{%v}?", stash.index),
+			func() error {
+				return summaryView.repoController.DropStash(stash)
+			},
+		},
+	})
+
+	return
+}
+
+func showSelectedStashDiff(summaryView *SummaryView, action Action) (err error) {
+	stash, isStash := summaryView.selectedStash()
+	if !isStash {
+		return
+	}
+
+	summaryView.channels.DoAction(Action{
+		ActionType: ActionShowStashDiff,
+		Args:       []interface{}{stash},
+	})
+
+	return
+}
+
+func stageSelectedFile(summaryView *SummaryView, action Action) (err error) {
+	statusFileLine, isStatusFile := summaryView.selectedStatusFile()
+	if !isStatusFile {
+		return
+	}
+
+	return summaryView.repoController.StageFile(statusFileLine.statusEntry.NewFilePath())
+}
+
+func unstageSelectedFile(summaryView *SummaryView, action Action) (err error) {
+	statusFileLine, isStatusFile := summaryView.selectedStatusFile()
+	if !isStatusFile {
+		return
+	}
+
+	return summaryView.repoController.UnstageFile(statusFileLine.statusEntry.NewFilePath())
+}
+
+func discardSelectedFile(summaryView *SummaryView, action Action) (err error) {
+	statusFileLine, isStatusFile := summaryView.selectedStatusFile()
+	if !isStatusFile {
+		return
+	}
+
+	filePath := statusFileLine.statusEntry.NewFilePath()
+
+	summaryView.channels.DoAction(Action{
+		ActionType: ActionConfirm,
+		Args: []interface{}{
+			fmt.Sprintf("Are you sure you want to discard changes to %v?", filePath),
+			func() error {
+				return summaryView.repoController.DiscardFile(filePath)
+			},
+		},
+	})
+
+	return
+}
+
+func showSelectedFileDiff(summaryView *SummaryView, action Action) (err error) {
+	statusFileLine, isStatusFile := summaryView.selectedStatusFile()
+	if !isStatusFile {
+		return
+	}
+
+	summaryView.channels.DoAction(Action{
+		ActionType: ActionShowFileDiff,
+		Args:       []interface{}{statusFileLine.statusEntry.NewFilePath()},
+	})
+
+	return
+}
+
+// stageSelectedFileHunks opens the diff view scoped to the selected file with hunk-level
+// stage/unstage actions enabled, so individual hunks can be staged rather than the whole file
+func stageSelectedFileHunks(summaryView *SummaryView, action Action) (err error) {
+	statusFileLine, isStatusFile := summaryView.selectedStatusFile()
+	if !isStatusFile {
+		return
+	}
+
+	summaryView.channels.DoAction(Action{
+		ActionType: ActionShowFileDiff,
+		Args:       []interface{}{statusFileLine.statusEntry.NewFilePath(), true},
+	})
+
+	return
+}
+
+func openSelectedCIRun(summaryView *SummaryView, action Action) (err error) {
+	run, isCIRun := summaryView.selectedCIRun()
+	if !isCIRun || run.URL == "" {
+		return
+	}
+
+	summaryView.channels.DoAction(Action{
+		ActionType: ActionOpenURL,
+		Args:       []interface{}{run.URL},
+	})
+
+	return
+}
+
+func initSelectedSubmodule(summaryView *SummaryView, action Action) (err error) {
+	submodule, isSubmodule := summaryView.selectedSubmodule()
+	if !isSubmodule {
+		return
+	}
+
+	return summaryView.repoController.SubmoduleUpdate(submodule.Path, true, false)
+}
+
+func updateSelectedSubmodule(summaryView *SummaryView, action Action) (err error) {
+	submodule, isSubmodule := summaryView.selectedSubmodule()
+	if !isSubmodule {
+		return
+	}
+
+	return summaryView.repoController.SubmoduleUpdate(submodule.Path, false, true)
+}
+
+func syncSelectedSubmodule(summaryView *SummaryView, action Action) (err error) {
+	submodule, isSubmodule := summaryView.selectedSubmodule()
+	if !isSubmodule {
+		return
+	}
+
+	return summaryView.repoController.SubmoduleSync(submodule.Path)
+}
+
+func descendIntoSelectedSubmodule(summaryView *SummaryView, action Action) (err error) {
+	submodule, isSubmodule := summaryView.selectedSubmodule()
+	if !isSubmodule || submodule.Uninitialized {
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], submodule.Path)
+	// submodule.Path is relative to the repo root, not grv's own working directory
+	cmd.Dir = summaryView.repoData.Path()
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	summaryView.channels.DoAction(Action{
+		ActionType: ActionRunCommandImmediate,
+		Args:       []interface{}{cmd},
+	})
+
+	return
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDivergenceGraphChars(t *testing.T) {
+	tests := []struct {
+		ahead, behind, width  uint
+		wantAhead, wantBehind uint
+	}{
+		{ahead: 0, behind: 0, width: 10, wantAhead: 0, wantBehind: 0},
+		{ahead: 0, behind: 0, width: 0, wantAhead: 0, wantBehind: 0},
+		{ahead: 5, behind: 0, width: 0, wantAhead: 0, wantBehind: 0},
+		{ahead: 5, behind: 5, width: 10, wantAhead: 5, wantBehind: 5},
+		{ahead: 1, behind: 0, width: 10, wantAhead: 10, wantBehind: 0},
+		{ahead: 0, behind: 1, width: 10, wantAhead: 0, wantBehind: 10},
+		{ahead: 1, behind: 1, width: 1, wantAhead: 0, wantBehind: 1},
+		{ahead: 1, behind: 99, width: 1, wantAhead: 0, wantBehind: 1},
+		{ahead: 99, behind: 1, width: 1, wantAhead: 0, wantBehind: 1},
+		{ahead: 1, behind: 1, width: 2, wantAhead: 1, wantBehind: 1},
+	}
+
+	for _, tt := range tests {
+		aheadChars, behindChars := divergenceGraphChars(tt.ahead, tt.behind, tt.width)
+
+		if aheadChars != tt.wantAhead || behindChars != tt.wantBehind {
+			t.Errorf("divergenceGraphChars(%d, %d, %d) = (%d, %d), want (%d, %d)",
+				tt.ahead, tt.behind, tt.width, aheadChars, behindChars, tt.wantAhead, tt.wantBehind)
+		}
+
+		if tt.ahead+tt.behind > 0 && tt.width > 0 && aheadChars+behindChars != tt.width {
+			t.Errorf("divergenceGraphChars(%d, %d, %d) allocated %d chars, want exactly %d",
+				tt.ahead, tt.behind, tt.width, aheadChars+behindChars, tt.width)
+		}
+	}
+}
+
+func TestGhRunDuration(t *testing.T) {
+	started := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		startedAt time.Time
+		updatedAt time.Time
+		want      time.Duration
+	}{
+		{name: "zero StartedAt yields unknown duration", startedAt: time.Time{}, updatedAt: started, want: unknownCIRunDuration},
+		{name: "completed run", startedAt: started, updatedAt: started.Add(90 * time.Second), want: 90 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := ghRunDuration(tt.startedAt, tt.updatedAt); got != tt.want {
+			t.Errorf("%v: ghRunDuration(%v, %v) = %v, want %v", tt.name, tt.startedAt, tt.updatedAt, got, tt.want)
+		}
+	}
+}
+
+func TestFormatCIRunDuration(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		want     string
+	}{
+		{duration: unknownCIRunDuration, want: "--:--"},
+		{duration: 0, want: "00:00"},
+		{duration: 90 * time.Second, want: "01:30"},
+		{duration: 61*time.Minute + 5*time.Second, want: "61:05"},
+	}
+
+	for _, tt := range tests {
+		if got := formatCIRunDuration(tt.duration); got != tt.want {
+			t.Errorf("formatCIRunDuration(%v) = %q, want %q", tt.duration, got, tt.want)
+		}
+	}
+}
+
+func TestGhRunStatus(t *testing.T) {
+	tests := []struct {
+		status     string
+		conclusion string
+		want       CIRunStatus
+	}{
+		{status: "queued", conclusion: "", want: CIRunQueued},
+		{status: "in_progress", conclusion: "", want: CIRunRunning},
+		{status: "completed", conclusion: "success", want: CIRunSuccess},
+		{status: "completed", conclusion: "failure", want: CIRunFailure},
+		{status: "completed", conclusion: "cancelled", want: CIRunFailure},
+	}
+
+	for _, tt := range tests {
+		if got := ghRunStatus(tt.status, tt.conclusion); got != tt.want {
+			t.Errorf("ghRunStatus(%q, %q) = %v, want %v", tt.status, tt.conclusion, got, tt.want)
+		}
+	}
+}
+
+func TestSubmoduleLineStatusFlag(t *testing.T) {
+	tests := []struct {
+		name      string
+		submodule *Submodule
+		want      string
+	}{
+		{
+			name:      "conflicted takes precedence over everything else",
+			submodule: &Submodule{Uninitialized: true, Conflicted: true, SHA: "a", RecordedSHA: "b"},
+			want:      "U",
+		},
+		{
+			name:      "uninitialized takes precedence over SHA mismatch",
+			submodule: &Submodule{Uninitialized: true, SHA: "a", RecordedSHA: "b"},
+			want:      "-",
+		},
+		{
+			name:      "SHA mismatch",
+			submodule: &Submodule{SHA: "a", RecordedSHA: "b"},
+			want:      "+",
+		},
+		{
+			name:      "up to date",
+			submodule: &Submodule{SHA: "a", RecordedSHA: "a"},
+			want:      " ",
+		},
+	}
+
+	for _, tt := range tests {
+		line := &submoduleLine{submodule: tt.submodule}
+		if got := line.statusFlag(); got != tt.want {
+			t.Errorf("%v: statusFlag() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
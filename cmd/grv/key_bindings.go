@@ -0,0 +1,74 @@
+package main
+
+// KeyBindingManager maps keystrokes to actions on a per view basis
+type KeyBindingManager interface {
+	// SetActionBinding binds keystring to actionType for viewID, so that pressing keystring while
+	// viewID is active dispatches actionType
+	SetActionBinding(viewID ViewID, keystring string, actionType ActionType)
+}
+
+// summaryViewStashKeyBindings are the default key bindings for the stash actions added to
+// SummaryView
+var summaryViewStashKeyBindings = map[string]ActionType{
+	"A": ActionStashApply,
+	"P": ActionStashPop,
+	"X": ActionStashDrop,
+	"V": ActionStashShowDiff,
+}
+
+// registerSummaryViewStashKeyBindings registers the default key bindings for the stash actions
+// added to SummaryView
+func registerSummaryViewStashKeyBindings(keyBindings KeyBindingManager) {
+	for keystring, actionType := range summaryViewStashKeyBindings {
+		keyBindings.SetActionBinding(ViewGitSummary, keystring, actionType)
+	}
+}
+
+// summaryViewFileKeyBindings are the default key bindings for the status file actions added to
+// SummaryView
+var summaryViewFileKeyBindings = map[string]ActionType{
+	"s": ActionStageFile,
+	"u": ActionUnstageFile,
+	"D": ActionDiscardFile,
+	"d": ActionShowFileDiff,
+	"h": ActionStageFileHunks,
+}
+
+// registerSummaryViewFileKeyBindings registers the default key bindings for the status file
+// actions added to SummaryView
+func registerSummaryViewFileKeyBindings(keyBindings KeyBindingManager) {
+	for keystring, actionType := range summaryViewFileKeyBindings {
+		keyBindings.SetActionBinding(ViewGitSummary, keystring, actionType)
+	}
+}
+
+// summaryViewCIKeyBindings are the default key bindings for the CI status actions added to
+// SummaryView
+var summaryViewCIKeyBindings = map[string]ActionType{
+	"o": ActionOpenCIRunURL,
+}
+
+// registerSummaryViewCIKeyBindings registers the default key bindings for the CI status actions
+// added to SummaryView
+func registerSummaryViewCIKeyBindings(keyBindings KeyBindingManager) {
+	for keystring, actionType := range summaryViewCIKeyBindings {
+		keyBindings.SetActionBinding(ViewGitSummary, keystring, actionType)
+	}
+}
+
+// summaryViewSubmoduleKeyBindings are the default key bindings for the submodule actions added to
+// SummaryView
+var summaryViewSubmoduleKeyBindings = map[string]ActionType{
+	"I":       ActionSubmoduleInit,
+	"U":       ActionSubmoduleUpdate,
+	"Y":       ActionSubmoduleSync,
+	"<Enter>": ActionSubmoduleDescend,
+}
+
+// registerSummaryViewSubmoduleKeyBindings registers the default key bindings for the submodule
+// actions added to SummaryView
+func registerSummaryViewSubmoduleKeyBindings(keyBindings KeyBindingManager) {
+	for keystring, actionType := range summaryViewSubmoduleKeyBindings {
+		keyBindings.SetActionBinding(ViewGitSummary, keystring, actionType)
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+// ThemeComponentID identifies a themeable UI component
+type ThemeComponentID int
+
+// The set of themeable components used by SummaryView
+const (
+	CmpSummaryViewHeader ThemeComponentID = iota
+	CmpSummaryViewNormal
+	CmpSummaryViewBranchAhead
+	CmpSummaryViewBranchBehind
+	CmpSummaryViewStagedFile
+	CmpSummaryViewUnstagedFile
+	CmpSummaryViewNoModifiedFiles
+	CmpSummaryViewStashIndex
+	CmpSummaryViewDivergenceGraph
+	CmpSummaryViewCIStatusSuccess
+	CmpSummaryViewCIStatusFailure
+	CmpSummaryViewCIStatusRunning
+	CmpSummaryViewCIStatusQueued
+	CmpSummaryViewSubmoduleStatus
+)